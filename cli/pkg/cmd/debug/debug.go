@@ -0,0 +1,269 @@
+// Package debug implements `sqoop debug`, a command group for inspecting
+// Sqoop's live resolver state directly, without going through the GraphQL
+// endpoint.
+package debug
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vektah/gqlgen/neelance/schema"
+
+	"github.com/solo-io/solo-kit/pkg/api/v1/clients"
+
+	"github.com/solo-io/sqoop/cli/pkg/helpers"
+	"github.com/solo-io/sqoop/pkg/dynamic"
+)
+
+type options struct {
+	useMemory       bool
+	namespace       string
+	schemaName      string
+	resolverMapName string
+}
+
+// Cmd builds the `sqoop debug` command group.
+func Cmd() *cobra.Command {
+	opts := &options{}
+
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "inspect Sqoop's live resolver state without hitting the GraphQL endpoint",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			if opts.useMemory {
+				helpers.UseMemoryClients()
+			}
+		},
+	}
+	cmd.PersistentFlags().BoolVar(&opts.useMemory, "memory", false, "use in-memory clients instead of a real cluster, so these commands work in CI")
+	cmd.PersistentFlags().StringVar(&opts.namespace, "namespace", "", "namespace to read the Schema/ResolverMap from (defaults to the first allowed namespace)")
+	cmd.PersistentFlags().StringVar(&opts.schemaName, "schema", "", "name of the Schema CR to use")
+	cmd.PersistentFlags().StringVar(&opts.resolverMapName, "resolver-map", "", "name of the ResolverMap CR to use")
+
+	cmd.AddCommand(
+		resolversCmd(opts),
+		schemaCmd(opts),
+		crdCmd(opts),
+	)
+	return cmd
+}
+
+func (o *options) resolveNamespace() string {
+	if o.namespace != "" {
+		return o.namespace
+	}
+	namespaces := helpers.MustGetNamespaces()
+	if len(namespaces) == 0 {
+		return ""
+	}
+	return namespaces[0]
+}
+
+// loadSchema reads the Schema CR named by --schema (in --namespace) and
+// parses its SDL.
+func (o *options) loadSchema(ctx context.Context) (*schema.Schema, error) {
+	client, err := helpers.SchemaClient()
+	if err != nil {
+		return nil, err
+	}
+	sch, err := client.Read(o.resolveNamespace(), o.schemaName, clients.ReadOpts{Ctx: ctx})
+	if err != nil {
+		return nil, err
+	}
+	return schema.Parse(sch.Text, false)
+}
+
+// loadResolverMap builds a dynamic.ResolverMap from the Schema and
+// ResolverMap CRs, via helpers.BuildResolverMap. Its
+// resolvers are only populated when the ResolverMap CR named by
+// --resolver-map carries a spec.template - ad hoc Go resolver funcs wired
+// up at server startup aren't introspectable this way, so those fields
+// show up with no resolver attached.
+func (o *options) loadResolverMap(ctx context.Context) (*dynamic.ResolverMap, error) {
+	return helpers.BuildResolverMap(ctx, o.resolveNamespace(), o.schemaName, o.resolverMapName)
+}
+
+func resolversCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resolvers",
+		Short: "inspect the live ResolverMap.Types",
+	}
+	cmd.AddCommand(resolversListCmd(opts), resolversResolveCmd(opts))
+	return cmd
+}
+
+func resolversListCmd(opts *options) *cobra.Command {
+	var typeFilter string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "dump the keys of ResolverMap.Types and each TypeResolver.Fields",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rm, err := opts.loadResolverMap(cmd.Context())
+			if err != nil {
+				return err
+			}
+			for typ, tr := range rm.Types {
+				if typeFilter != "" && typ.TypeName() != typeFilter {
+					continue
+				}
+				fmt.Println(typ.TypeName())
+				for field := range tr.Fields {
+					fmt.Printf("  %v\n", field)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&typeFilter, "type", "", "only list fields for this type")
+	return cmd
+}
+
+func resolversResolveCmd(opts *options) *cobra.Command {
+	var rawArgs string
+	cmd := &cobra.Command{
+		Use:   "resolve <Type> <field>",
+		Short: "invoke ResolverMap.Resolve with a synthesized Params and print the raw result and error",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rm, err := opts.loadResolverMap(cmd.Context())
+			if err != nil {
+				return err
+			}
+			typ, ok := namedType(rm, args[0])
+			if !ok {
+				return fmt.Errorf("unknown type %v", args[0])
+			}
+			result, err := rm.Resolve(typ, args[1], dynamic.Params{Args: parseArgs(rawArgs)})
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "error: %v\n", err)
+				return nil
+			}
+			fmt.Printf("%v\n", result)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&rawArgs, "args", "", "comma-separated k=v pairs passed as the resolver's Args")
+	return cmd
+}
+
+func namedType(rm *dynamic.ResolverMap, name string) (schema.NamedType, bool) {
+	for typ := range rm.Types {
+		if typ.TypeName() == name {
+			return typ, true
+		}
+	}
+	return nil, false
+}
+
+func parseArgs(raw string) map[string]interface{} {
+	args := make(map[string]interface{})
+	if raw == "" {
+		return args
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		args[kv[0]] = kv[1]
+	}
+	return args
+}
+
+func schemaCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "inspect the live Schema",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "print",
+		Short: "render the parsed *schema.Schema back to SDL",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sch, err := opts.loadSchema(cmd.Context())
+			if err != nil {
+				return err
+			}
+			fmt.Print(renderSDL(sch))
+			return nil
+		},
+	})
+	return cmd
+}
+
+func crdCmd(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "crd",
+		Short: "fetch and pretty-print CRs via the helpers clients",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:       "dump [schemas|resolvermaps|settings]",
+		Short:     "fetch and pretty-print the given CRs from all allowed namespaces",
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: []string{"schemas", "resolvermaps", "settings"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch args[0] {
+			case "schemas":
+				return dumpSchemas(cmd.Context())
+			case "resolvermaps":
+				return dumpResolverMaps(cmd.Context())
+			case "settings":
+				return dumpSettings(cmd.Context())
+			}
+			return nil
+		},
+	})
+	return cmd
+}
+
+func dumpSchemas(ctx context.Context) error {
+	client, err := helpers.SchemaClient()
+	if err != nil {
+		return err
+	}
+	for _, namespace := range helpers.MustGetNamespaces() {
+		schemas, err := client.List(namespace, clients.ListOpts{Ctx: ctx})
+		if err != nil {
+			return err
+		}
+		for _, sch := range schemas {
+			fmt.Printf("%+v\n", sch)
+		}
+	}
+	return nil
+}
+
+func dumpResolverMaps(ctx context.Context) error {
+	client, err := helpers.ResolverMapClient()
+	if err != nil {
+		return err
+	}
+	for _, namespace := range helpers.MustGetNamespaces() {
+		resolverMaps, err := client.List(namespace, clients.ListOpts{Ctx: ctx})
+		if err != nil {
+			return err
+		}
+		for _, rm := range resolverMaps {
+			fmt.Printf("%+v\n", rm)
+		}
+	}
+	return nil
+}
+
+func dumpSettings(ctx context.Context) error {
+	client, err := helpers.SettingsClient()
+	if err != nil {
+		return err
+	}
+	for _, namespace := range helpers.MustGetNamespaces() {
+		settingsList, err := client.List(namespace, clients.ListOpts{Ctx: ctx})
+		if err != nil {
+			return err
+		}
+		for _, s := range settingsList {
+			fmt.Printf("%+v\n", s)
+		}
+	}
+	return nil
+}
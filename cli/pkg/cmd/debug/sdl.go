@@ -0,0 +1,60 @@
+package debug
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vektah/gqlgen/neelance/schema"
+
+	"github.com/solo-io/sqoop/pkg/dynamic"
+)
+
+// renderSDL renders the types of a parsed *schema.Schema back into SDL, for
+// operators comparing what Sqoop actually parsed against what they
+// authored. It's a best-effort printer covering the type kinds Sqoop's
+// resolver map cares about (Object, Interface, Union); other kinds are
+// rendered as a bare "type Name" declaration. Built-in scalar and
+// introspection types are skipped, the same ones dynamic.NewResolverMap
+// skips, so this only prints the types Sqoop's resolver map actually cares
+// about.
+func renderSDL(sch *schema.Schema) string {
+	var b strings.Builder
+	for _, t := range sch.Types {
+		if dynamic.IsMetaType(t.TypeName()) {
+			continue
+		}
+		switch t := t.(type) {
+		case *schema.Object:
+			fmt.Fprintf(&b, "type %v {\n", t.Name)
+			renderFields(&b, t.Fields)
+			b.WriteString("}\n\n")
+		case *schema.Interface:
+			fmt.Fprintf(&b, "interface %v {\n", t.Name)
+			renderFields(&b, t.Fields)
+			b.WriteString("}\n\n")
+		case *schema.Union:
+			names := make([]string, len(t.PossibleTypes))
+			for i, p := range t.PossibleTypes {
+				names[i] = p.Name
+			}
+			fmt.Fprintf(&b, "union %v = %v\n\n", t.Name, strings.Join(names, " | "))
+		default:
+			fmt.Fprintf(&b, "type %v\n\n", t.TypeName())
+		}
+	}
+	return b.String()
+}
+
+func renderFields(b *strings.Builder, fields []*schema.Field) {
+	for _, f := range fields {
+		args := make([]string, len(f.Args))
+		for i, a := range f.Args {
+			args[i] = fmt.Sprintf("%v: %v", a.Name.Name, a.Type.String())
+		}
+		if len(args) == 0 {
+			fmt.Fprintf(b, "  %v: %v\n", f.Name, f.Type.String())
+			continue
+		}
+		fmt.Fprintf(b, "  %v(%v): %v\n", f.Name, strings.Join(args, ", "), f.Type.String())
+	}
+}
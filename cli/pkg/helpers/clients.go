@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"github.com/solo-io/sqoop/pkg/api/v1"
+	"sync"
 	"time"
 
+	"github.com/gogo/protobuf/types"
 	glooV1 "github.com/solo-io/gloo/projects/gloo/pkg/api/v1"
 	"github.com/solo-io/gloo/projects/gloo/pkg/defaults"
 	"github.com/solo-io/solo-kit/pkg/api/v1/clients/factory"
@@ -21,12 +23,90 @@ import (
 
 var memoryResourceClient *factory.MemoryResourceClientFactory
 
+// allowedNamespaces, when non-empty, restricts Sqoop to watching only these
+// namespaces instead of requiring cluster-wide RBAC to list/watch everything.
+// Populated via SetAllowedNamespaces, typically from the "watchNamespaces"
+// field of the sqoop extension config on a Gloo Settings resource (see
+// ConfigureFromSettings).
+var allowedNamespaces []string
+
+// loggedNamespaces tracks which namespaces we've already logged as "seen",
+// so operators can tell which namespaces Sqoop is actually watching without
+// flooding the logs on every reconcile. GetNamespaces is called from
+// multiple goroutines once the scheduler is running, so access is guarded
+// by loggedNamespacesMu.
+var (
+	loggedNamespacesMu sync.Mutex
+	loggedNamespaces   = make(map[string]bool)
+)
+
 func UseMemoryClients() {
 	memoryResourceClient = &factory.MemoryResourceClientFactory{
 		Cache: memory.NewInMemoryResourceCache(),
 	}
 }
 
+// SetAllowedNamespaces restricts every Kube watch created by this package's
+// client factories (Schema, ResolverMap, Settings, Secret) to the given set
+// of namespaces. Passing an empty slice restores the default cluster-wide
+// behavior.
+func SetAllowedNamespaces(namespaces []string) {
+	allowedNamespaces = namespaces
+}
+
+// sqoopExtensionKey is the key Sqoop's own config is stored under in a Gloo
+// Settings resource's extensions, the generic mechanism Gloo gives plugins
+// for carrying arbitrary per-plugin config without adding fields to the
+// shared Settings proto itself.
+const sqoopExtensionKey = "sqoop"
+
+// ConfigureFromSettings applies Sqoop's operator-facing configuration out of
+// a Gloo Settings resource's extensions (settings.extensions.configs["sqoop"]),
+// so the namespace allow-list and sync cadence come from Settings rather
+// than from the deployment. The values apply only to clients and
+// schedulers created after the call; ones already built keep the
+// namespaces and intervals they were created with. A Settings resource
+// with no "sqoop" extension config leaves every setting unchanged.
+func ConfigureFromSettings(settings *glooV1.Settings) {
+	cfg := sqoopExtensionConfig(settings)
+	if cfg == nil {
+		return
+	}
+
+	SetAllowedNamespaces(stringListField(cfg, "watchNamespaces"))
+	SetSyncIntervals(durationField(cfg, "resolverMapSyncInterval"), durationField(cfg, "schemaValidationInterval"))
+}
+
+func sqoopExtensionConfig(settings *glooV1.Settings) *types.Struct {
+	if settings == nil || settings.Extensions == nil {
+		return nil
+	}
+	return settings.Extensions.Configs[sqoopExtensionKey]
+}
+
+func stringListField(s *types.Struct, key string) []string {
+	list := s.Fields[key].GetListValue()
+	if list == nil {
+		return nil
+	}
+	values := make([]string, 0, len(list.Values))
+	for _, v := range list.Values {
+		values = append(values, v.GetStringValue())
+	}
+	return values
+}
+
+// durationField parses key as a Go duration string (e.g. "30s"), returning
+// zero - which SetSyncIntervals treats as "leave unchanged" - if the
+// field is absent or isn't a valid duration.
+func durationField(s *types.Struct, key string) time.Duration {
+	d, err := time.ParseDuration(s.Fields[key].GetStringValue())
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
 func MustGetNamespaces() []string {
 	ns, err := GetNamespaces()
 	if err != nil {
@@ -35,8 +115,13 @@ func MustGetNamespaces() []string {
 	return ns
 }
 
-// Note: requires RBAC permission to list namespaces at the cluster level
+// Note: requires RBAC permission to list namespaces at the cluster level,
+// unless SetAllowedNamespaces has been called with an explicit allow-list.
 func GetNamespaces() ([]string, error) {
+	if len(allowedNamespaces) > 0 {
+		return allowedNamespaces, nil
+	}
+
 	if memoryResourceClient != nil {
 		return []string{"default", defaults.GlooSystem}, nil
 	}
@@ -56,10 +141,25 @@ func GetNamespaces() ([]string, error) {
 	}
 	for _, ns := range nsList.Items {
 		namespaces = append(namespaces, ns.Name)
+		logNamespaceSeen(ns.Name)
 	}
 	return namespaces, nil
 }
 
+// logNamespaceSeen logs the first time we observe a given namespace, so
+// operators can debug missing-namespace issues (e.g. "why isn't Sqoop
+// picking up my Schema?") without re-logging the same namespace forever.
+func logNamespaceSeen(namespace string) {
+	loggedNamespacesMu.Lock()
+	defer loggedNamespacesMu.Unlock()
+
+	if loggedNamespaces[namespace] {
+		return
+	}
+	loggedNamespaces[namespace] = true
+	log.Printf("watching namespace %v", namespace)
+}
+
 func MustSchemaClient() v1.SchemaClient {
 	client, err := SchemaClient()
 	if err != nil {
@@ -79,9 +179,10 @@ func SchemaClient() (v1.SchemaClient, error) {
 	}
 	cache := kube.NewKubeCache(context.TODO())
 	schemaClient, err := v1.NewSchemaClient(&factory.KubeResourceClientFactory{
-		Crd:         v1.SchemaCrd,
-		Cfg:         cfg,
-		SharedCache: cache,
+		Crd:                v1.SchemaCrd,
+		Cfg:                cfg,
+		SharedCache:        cache,
+		NamespaceWhitelist: allowedNamespaces,
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "creating resolver map client")
@@ -92,6 +193,10 @@ func SchemaClient() (v1.SchemaClient, error) {
 	return schemaClient, nil
 }
 
+// MustResolverMapClient returns the raw CRUD client for the ResolverMap
+// CRD. It does not expand spec.template itself - a ResolverMap CR carrying
+// a template only gets expanded when read through BuildResolverMap, which
+// `sqoop debug` uses. The scheduler's sync jobs do not expand templates.
 func MustResolverMapClient() v1.ResolverMapClient {
 	client, err := ResolverMapClient()
 	if err != nil {
@@ -111,9 +216,10 @@ func ResolverMapClient() (v1.ResolverMapClient, error) {
 	}
 	cache := kube.NewKubeCache(context.TODO())
 	resolverMapClient, err := v1.NewResolverMapClient(&factory.KubeResourceClientFactory{
-		Crd:         v1.ResolverMapCrd,
-		Cfg:         cfg,
-		SharedCache: cache,
+		Crd:                v1.ResolverMapCrd,
+		Cfg:                cfg,
+		SharedCache:        cache,
+		NamespaceWhitelist: allowedNamespaces,
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "creating resolver map client")
@@ -143,9 +249,10 @@ func SettingsClient() (glooV1.SettingsClient, error) {
 	}
 	cache := kube.NewKubeCache(context.TODO())
 	settingsClient, err := glooV1.NewSettingsClient(&factory.KubeResourceClientFactory{
-		Crd:         glooV1.SettingsCrd,
-		Cfg:         cfg,
-		SharedCache: cache,
+		Crd:                glooV1.SettingsCrd,
+		Cfg:                cfg,
+		SharedCache:        cache,
+		NamespaceWhitelist: allowedNamespaces,
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "creating settings client")
@@ -174,7 +281,8 @@ func secretClient() (glooV1.SecretClient, error) {
 		return nil, errors.Wrapf(err, "getting kube config")
 	}
 	secretClient, err := glooV1.NewSecretClient(&factory.KubeSecretClientFactory{
-		Clientset: clientset,
+		Clientset:          clientset,
+		NamespaceWhitelist: allowedNamespaces,
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "creating Secrets client")
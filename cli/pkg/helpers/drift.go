@@ -0,0 +1,302 @@
+package helpers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/solo-io/solo-kit/pkg/api/v1/clients"
+	"github.com/solo-io/solo-kit/pkg/api/v1/resources"
+	"github.com/solo-io/solo-kit/pkg/api/v1/resources/core"
+	"github.com/solo-io/solo-kit/pkg/errors"
+
+	"github.com/solo-io/sqoop/pkg/api/v1"
+)
+
+const (
+	// ChecksumAnnotationKey stores a stable hash of a resource's spec,
+	// excluding status and metadata, so it only changes when the
+	// user-authored content of the resource changes.
+	ChecksumAnnotationKey = "sqoop.solo.io/checksum"
+
+	// ManagedByAnnotationKey stores the caller-supplied identity that wrote
+	// a resource, so DetectDrift knows which resources it's responsible for.
+	ManagedByAnnotationKey = "sqoop.solo.io/managed-by"
+)
+
+// DriftedResource describes a Schema or ResolverMap whose live checksum no
+// longer matches the one recorded in its sqoop.solo.io/checksum annotation,
+// meaning it was edited out-of-band (e.g. a `kubectl edit`) since Sqoop last
+// wrote it.
+type DriftedResource struct {
+	Kind              string
+	Namespace         string
+	Name              string
+	AnnotatedChecksum string
+	ActualChecksum    string
+}
+
+// lastAppliedMu guards lastApplied, the in-process cache of the last spec
+// EnsureManaged wrote for each resource, keyed by "kind/namespace/name".
+// It's what lets the resolver-map reconciler job re-apply the spec Sqoop
+// actually intended, rather than re-stamping whatever out-of-band edit it
+// finds live.
+var (
+	lastAppliedMu sync.Mutex
+	lastApplied   = map[string]resources.InputResource{}
+)
+
+// EnsureManaged stamps resource with a checksum of its spec and a
+// managed-by annotation identifying owner, then writes it through the
+// appropriate client. If the live object's spec already hashes to the same
+// checksum, the write is skipped entirely - even when the live object's
+// annotations differ from the stamped ones - so Sqoop's reconcile loops
+// don't spin on no-op updates. Either way, a clone of resource is
+// remembered as the last-known-good spec for its kind/namespace/name, so
+// DetectDrift's findings can be re-applied later via LastAppliedResolverMap.
+func EnsureManaged(ctx context.Context, resource resources.InputResource, owner string) error {
+	// kindOf is the single source of truth for which resource types
+	// EnsureManaged supports; the switch below still has to name the
+	// concrete types because the generated Schema/ResolverMap clients are
+	// statically typed, but adding a type here means adding it to kindOf
+	// too, or rememberLastApplied will silently stop caching it.
+	if _, ok := kindOf(resource); !ok {
+		return errors.Errorf("EnsureManaged does not support resource type %T", resource)
+	}
+
+	sum, err := checksum(resource)
+	if err != nil {
+		return errors.Wrapf(err, "computing checksum")
+	}
+	stampManaged(resource, sum, owner)
+
+	unchanged, err := liveChecksumMatches(resource, sum)
+	if err != nil {
+		return err
+	}
+	if !unchanged {
+		switch res := resource.(type) {
+		case *v1.Schema:
+			client, err := SchemaClient()
+			if err != nil {
+				return err
+			}
+			if _, err := client.Write(res, clients.WriteOpts{Ctx: ctx, OverwriteExisting: true}); err != nil {
+				return err
+			}
+		case *v1.ResolverMap:
+			client, err := ResolverMapClient()
+			if err != nil {
+				return err
+			}
+			if _, err := client.Write(res, clients.WriteOpts{Ctx: ctx, OverwriteExisting: true}); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("EnsureManaged does not support resource type %T", resource)
+		}
+	}
+
+	rememberLastApplied(resource)
+	return nil
+}
+
+func rememberLastApplied(resource resources.InputResource) {
+	meta := resource.GetMetadata()
+	kind, ok := kindOf(resource)
+	if !ok {
+		return
+	}
+
+	lastAppliedMu.Lock()
+	defer lastAppliedMu.Unlock()
+	lastApplied[lastAppliedKey(kind, meta.Namespace, meta.Name)] = resources.Clone(resource).(resources.InputResource)
+}
+
+// LastAppliedResolverMap returns the last spec this process saw as
+// consistent with its own checksum annotation for the ResolverMap CR named
+// namespace/name - either because this process wrote it via EnsureManaged,
+// or because a prior DetectDrift sweep observed it un-drifted. The
+// resolver-map reconciler job uses this to restore a drifted ResolverMap
+// to what was last known good, instead of treating the drifted content
+// itself as the new desired state. Since the cache is in-process only,
+// ok is false until at least one clean sighting has happened since this
+// process started - a resource that's already drifted on the very first
+// sweep after a restart can't be auto-restored.
+func LastAppliedResolverMap(namespace, name string) (*v1.ResolverMap, bool) {
+	lastAppliedMu.Lock()
+	defer lastAppliedMu.Unlock()
+
+	resource, ok := lastApplied[lastAppliedKey("ResolverMap", namespace, name)]
+	if !ok {
+		return nil, false
+	}
+	resolverMap, ok := resource.(*v1.ResolverMap)
+	return resolverMap, ok
+}
+
+func lastAppliedKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+func kindOf(resource resources.InputResource) (string, bool) {
+	switch resource.(type) {
+	case *v1.Schema:
+		return "Schema", true
+	case *v1.ResolverMap:
+		return "ResolverMap", true
+	default:
+		return "", false
+	}
+}
+
+// DetectDrift lists every Schema and ResolverMap in the allowed namespaces
+// that's annotated as managed by owner, recomputes its checksum, and
+// reports the ones whose live checksum no longer matches the annotation
+// recorded at last write.
+func DetectDrift(ctx context.Context, owner string) ([]DriftedResource, error) {
+	var drifted []DriftedResource
+
+	schemaClient, err := SchemaClient()
+	if err != nil {
+		return nil, err
+	}
+	resolverMapClient, err := ResolverMapClient()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, namespace := range MustGetNamespaces() {
+		schemas, err := schemaClient.List(namespace, clients.ListOpts{Ctx: ctx})
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing schemas in %v", namespace)
+		}
+		for _, schema := range schemas {
+			drift, ok, err := detect("Schema", schema, owner)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				drifted = append(drifted, drift)
+			}
+		}
+
+		resolverMaps, err := resolverMapClient.List(namespace, clients.ListOpts{Ctx: ctx})
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing resolver maps in %v", namespace)
+		}
+		for _, resolverMap := range resolverMaps {
+			drift, ok, err := detect("ResolverMap", resolverMap, owner)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				drifted = append(drifted, drift)
+			}
+		}
+	}
+
+	return drifted, nil
+}
+
+func detect(kind string, resource resources.InputResource, owner string) (DriftedResource, bool, error) {
+	meta := resource.GetMetadata()
+	if meta.Annotations[ManagedByAnnotationKey] != owner {
+		return DriftedResource{}, false, nil
+	}
+	annotated := meta.Annotations[ChecksumAnnotationKey]
+
+	actual, err := checksum(resource)
+	if err != nil {
+		return DriftedResource{}, false, errors.Wrapf(err, "computing checksum for %v %v.%v", kind, meta.Namespace, meta.Name)
+	}
+	if actual == annotated {
+		// A resource that's consistent with its own checksum annotation is,
+		// by definition, a last-known-good copy - remember it so a later
+		// DetectDrift sweep has something to restore from even if this
+		// process itself never wrote the resource via EnsureManaged.
+		rememberLastApplied(resource)
+		return DriftedResource{}, false, nil
+	}
+
+	return DriftedResource{
+		Kind:              kind,
+		Namespace:         meta.Namespace,
+		Name:              meta.Name,
+		AnnotatedChecksum: annotated,
+		ActualChecksum:    actual,
+	}, true, nil
+}
+
+// liveChecksumMatches reports whether the live resource's actual spec
+// already hashes to sum. It recomputes the checksum from the live spec
+// itself rather than trusting the live object's checksum annotation, which
+// may be stale - a resource can drift (spec edited out-of-band) without
+// its old annotation changing, and it's exactly that case EnsureManaged
+// needs to catch so it still writes the restored spec.
+func liveChecksumMatches(resource resources.InputResource, sum string) (bool, error) {
+	meta := resource.GetMetadata()
+	var (
+		live resources.InputResource
+		err  error
+	)
+	switch resource.(type) {
+	case *v1.Schema:
+		client, clientErr := SchemaClient()
+		if clientErr != nil {
+			return false, clientErr
+		}
+		live, err = client.Read(meta.Namespace, meta.Name, clients.ReadOpts{})
+	case *v1.ResolverMap:
+		client, clientErr := ResolverMapClient()
+		if clientErr != nil {
+			return false, clientErr
+		}
+		live, err = client.Read(meta.Namespace, meta.Name, clients.ReadOpts{})
+	default:
+		return false, errors.Errorf("EnsureManaged does not support resource type %T", resource)
+	}
+	if err != nil {
+		if errors.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	liveSum, err := checksum(live)
+	if err != nil {
+		return false, errors.Wrapf(err, "computing checksum for live %v.%v", meta.Namespace, meta.Name)
+	}
+	return liveSum == sum, nil
+}
+
+func stampManaged(resource resources.InputResource, sum, owner string) {
+	meta := resource.GetMetadata()
+	if meta.Annotations == nil {
+		meta.Annotations = make(map[string]string)
+	}
+	meta.Annotations[ChecksumAnnotationKey] = sum
+	meta.Annotations[ManagedByAnnotationKey] = owner
+	resource.SetMetadata(meta)
+}
+
+// checksum computes a stable hash of resource's spec, excluding status and
+// metadata, so it only changes when the user-authored content changes.
+func checksum(resource resources.InputResource) (string, error) {
+	clone := resources.Clone(resource).(resources.InputResource)
+	clone.SetMetadata(core.Metadata{})
+	clone.SetStatus(core.Status{})
+
+	message, ok := clone.(proto.Message)
+	if !ok {
+		return "", errors.Errorf("resource %T is not a protobuf message", resource)
+	}
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
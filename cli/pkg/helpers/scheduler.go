@@ -0,0 +1,129 @@
+package helpers
+
+import (
+	"context"
+	"time"
+
+	"github.com/solo-io/solo-kit/pkg/api/v1/clients"
+	"github.com/solo-io/solo-kit/pkg/api/v1/resources/core"
+	"github.com/solo-io/solo-kit/pkg/utils/log"
+	"github.com/vektah/gqlgen/neelance/schema"
+
+	"github.com/solo-io/sqoop/pkg/sync/scheduler"
+)
+
+// syncOwner identifies Sqoop itself as the managed-by owner for resources
+// the scheduler's jobs reconcile, matching what a running Sqoop stamps on
+// write (see EnsureManaged).
+const syncOwner = "sqoop"
+
+const (
+	defaultResolverMapSyncInterval  = 30 * time.Second
+	defaultSchemaValidationInterval = time.Minute
+)
+
+// resolverMapSyncInterval and schemaValidationInterval are configurable via
+// the "resolverMapSyncInterval"/"schemaValidationInterval" fields of the
+// sqoop extension config on a Gloo Settings resource (ConfigureFromSettings).
+// MustScheduler reads them once, when it registers the jobs, so a change
+// made afterwards does not affect a Scheduler that is already built.
+var (
+	resolverMapSyncInterval  = defaultResolverMapSyncInterval
+	schemaValidationInterval = defaultSchemaValidationInterval
+)
+
+// SetSyncIntervals overrides the scheduler's built-in job cadences. A zero
+// or negative value leaves the corresponding interval unchanged.
+func SetSyncIntervals(resolverMapSync, schemaValidation time.Duration) {
+	if resolverMapSync > 0 {
+		resolverMapSyncInterval = resolverMapSync
+	}
+	if schemaValidation > 0 {
+		schemaValidationInterval = schemaValidation
+	}
+}
+
+// MustScheduler builds a Scheduler with Sqoop's built-in sync jobs
+// registered - a ResolverMapReconciler that re-applies any ResolverMap
+// whose live checksum has drifted from its last-applied spec, and a
+// SchemaValidator that re-parses every Schema and writes the result to its
+// status.
+// Callers start it alongside the other Must* clients during server
+// startup.
+func MustScheduler() *scheduler.Scheduler {
+	s := scheduler.NewScheduler()
+	s.Register("resolver-map-reconciler", resolverMapSyncInterval, resolverMapReconcilerJob)
+	s.Register("schema-validator", schemaValidationInterval, schemaValidatorJob)
+	return s
+}
+
+// resolverMapReconcilerJob re-applies any ResolverMap CR across the
+// allowed namespaces whose live checksum no longer matches the one
+// recorded in its sqoop.solo.io/checksum annotation, restoring it to its
+// last-known-good spec (via LastAppliedResolverMap) rather than adopting
+// the drifted content as the new desired state.
+func resolverMapReconcilerJob(ctx context.Context) error {
+	drifted, err := DetectDrift(ctx, syncOwner)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range drifted {
+		if d.Kind != "ResolverMap" {
+			continue
+		}
+		desired, ok := LastAppliedResolverMap(d.Namespace, d.Name)
+		if !ok {
+			log.Printf("resolver map %v.%v has drifted from its last-applied checksum, but this process has no last-known-good copy to restore (e.g. it hasn't written this resource since it started) - skipping", d.Namespace, d.Name)
+			continue
+		}
+		log.Printf("resolver map %v.%v has drifted from its last-applied checksum, restoring its last-known-good spec", d.Namespace, d.Name)
+		if err := EnsureManaged(ctx, desired, syncOwner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemaValidatorJob re-parses every Schema CR across the allowed
+// namespaces and writes the result to the CR's status, so a broken schema
+// shows up as Rejected on the resource itself - not just in Sqoop's own
+// logs - and is caught on a timer instead of only when a query hits it.
+func schemaValidatorJob(ctx context.Context) error {
+	client, err := SchemaClient()
+	if err != nil {
+		return err
+	}
+	for _, namespace := range MustGetNamespaces() {
+		schemas, err := client.List(namespace, clients.ListOpts{Ctx: ctx})
+		if err != nil {
+			return err
+		}
+		for _, sch := range schemas {
+			status := validationStatus(sch.Text)
+			if status == sch.GetStatus() {
+				continue
+			}
+
+			meta := sch.GetMetadata()
+			if status.State == core.Status_Rejected {
+				log.Printf("schema %v.%v failed validation: %v", meta.Namespace, meta.Name, status.Reason)
+			}
+
+			sch.SetStatus(status)
+			if _, err := client.Write(sch, clients.WriteOpts{Ctx: ctx, OverwriteExisting: true}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validationStatus re-parses sdl and reports the core.Status it implies:
+// Rejected with the parse error as its Reason, or Accepted.
+func validationStatus(sdl string) core.Status {
+	if _, err := schema.Parse(sdl, false); err != nil {
+		return core.Status{State: core.Status_Rejected, Reason: err.Error()}
+	}
+	return core.Status{State: core.Status_Accepted}
+}
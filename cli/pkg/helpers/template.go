@@ -0,0 +1,75 @@
+package helpers
+
+import (
+	"context"
+	"text/template"
+
+	"github.com/vektah/gqlgen/neelance/schema"
+
+	"github.com/solo-io/solo-kit/pkg/api/v1/clients"
+	"github.com/solo-io/solo-kit/pkg/errors"
+
+	"github.com/solo-io/sqoop/pkg/dynamic"
+	dynamictemplate "github.com/solo-io/sqoop/pkg/dynamic/template"
+)
+
+// ExpandResolverMapTemplate renders source - the contents of a ResolverMap
+// CR's spec.template - against sch and returns the resolvers it describes.
+// This is what lets a ResolverMap carry a single template instead of
+// enumerating every field.
+func ExpandResolverMapTemplate(sch *schema.Schema, source string) (map[string]dynamic.ResolverFunc, error) {
+	tmpl, err := template.New("resolvermap").Funcs(dynamictemplate.Funcs()).Parse(source)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing resolver map template")
+	}
+	resolvers, err := dynamictemplate.Generate(sch, tmpl)
+	if err != nil {
+		return nil, errors.Wrapf(err, "expanding resolver map template")
+	}
+	return resolvers, nil
+}
+
+// BuildResolverMap is the path from CRs to a usable
+// dynamic.ResolverMap: it reads the Schema CR named schemaName and the
+// ResolverMap CR named resolverMapName (both via MustSchemaClient's and
+// MustResolverMapClient's non-Must counterparts, so callers can handle the
+// error) out of namespace, and - when the ResolverMap CR carries a
+// spec.template - expands it with ExpandResolverMapTemplate before handing
+// the result to dynamic.NewResolverMap. Every caller that needs a live
+// ResolverMap, not just the field-by-field inputResolvers case, should go
+// through this rather than calling dynamic.NewResolverMap directly, so a
+// CR's template is always honored.
+func BuildResolverMap(ctx context.Context, namespace, schemaName, resolverMapName string) (*dynamic.ResolverMap, error) {
+	schemaClient, err := SchemaClient()
+	if err != nil {
+		return nil, err
+	}
+	schemaCR, err := schemaClient.Read(namespace, schemaName, clients.ReadOpts{Ctx: ctx})
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading schema %v.%v", namespace, schemaName)
+	}
+	sch, err := schema.Parse(schemaCR.Text, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing schema %v.%v", namespace, schemaName)
+	}
+
+	inputResolvers := map[string]dynamic.ResolverFunc{}
+	if resolverMapName != "" {
+		resolverMapClient, err := ResolverMapClient()
+		if err != nil {
+			return nil, err
+		}
+		resolverMapCR, err := resolverMapClient.Read(namespace, resolverMapName, clients.ReadOpts{Ctx: ctx})
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading resolver map %v.%v", namespace, resolverMapName)
+		}
+		if resolverMapCR.Template != "" {
+			inputResolvers, err = ExpandResolverMapTemplate(sch, resolverMapCR.Template)
+			if err != nil {
+				return nil, errors.Wrapf(err, "expanding template for resolver map %v.%v", namespace, resolverMapName)
+			}
+		}
+	}
+
+	return dynamic.NewResolverMap(sch, inputResolvers)
+}
@@ -10,6 +10,15 @@ import (
 type ResolverMap struct {
 	// resolvers for all named types
 	Types map[schema.NamedType]*TypeResolver
+
+	// same TypeResolvers as Types, keyed by name for fast lookup when
+	// dispatching a union field to the concrete type resolving it
+	byName map[string]*TypeResolver
+
+	// per-union type-discriminator resolvers, registered as "Union.__resolveType"
+	// in inputResolvers. Used to determine which concrete type a union value
+	// actually is, when the value itself doesn't implement Typed.
+	resolveTypeFns map[string]ResolverFunc
 }
 
 type TypeResolver struct {
@@ -28,8 +37,22 @@ type FieldResolver struct {
 // todo
 type ResolverFunc func(params Params) (interface{}, error)
 
-func NewResolverMap(sch *schema.Schema, inputResolvers map[string]ResolverFunc) *ResolverMap {
+// Typed is implemented by concrete values returned from a union-typed field
+// when they know their own GraphQL type name. Resolve uses it to dispatch a
+// union field access to the resolver registered for the concrete type,
+// falling back to a "Union.__resolveType" resolver (registered in
+// inputResolvers) when the source value doesn't implement it.
+type Typed interface {
+	Typename() string
+}
+
+const resolveTypeSuffix = ".__resolveType"
+
+func NewResolverMap(sch *schema.Schema, inputResolvers map[string]ResolverFunc) (*ResolverMap, error) {
 	typeMap := make(map[schema.NamedType]*TypeResolver)
+	byName := make(map[string]*TypeResolver)
+	var unions []*schema.Union
+
 	for _, t := range sch.Types {
 		if metaType(t.TypeName()) {
 			continue
@@ -53,26 +76,125 @@ func NewResolverMap(sch *schema.Schema, inputResolvers map[string]ResolverFunc)
 				fields[f.Name] = &FieldResolver{Type: f.Type, ResolverFunc: res}
 			}
 
-		// TODO: figure out union. should support all fields from all children. how to deal with name overlap?
 		case *schema.Union:
-			//for _, o := range t.PossibleTypes {
-			//	res := inputResolvers[t.Name+"."+o.Name]
-			//	if res == nil {
-			//		res = emptyResolver
-			//	}
-			//	fields[o.Name] = &FieldResolver{Type: o, ResolverFunc: res}
-			//}
+			// possible types are only guaranteed to exist in typeMap once every
+			// object has been visited, so unions are resolved in a second pass
+			// below.
+			unions = append(unions, t)
+			continue
 		}
 		if len(fields) == 0 {
 			continue
 		}
-		typeMap[t] = &TypeResolver{Fields: fields}
+		resolver := &TypeResolver{Fields: fields}
+		typeMap[t] = resolver
+		byName[t.TypeName()] = resolver
+	}
+
+	resolveTypeFns := make(map[string]ResolverFunc)
+	for _, u := range unions {
+		fields, err := unionFields(u, byName, inputResolvers)
+		if err != nil {
+			return nil, errors.Wrapf(err, "building resolver map for union %v", u.Name)
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		resolver := &TypeResolver{Fields: fields}
+		typeMap[u] = resolver
+		byName[u.Name] = resolver
+
+		if res, ok := inputResolvers[u.Name+resolveTypeSuffix]; ok {
+			resolveTypeFns[u.Name] = res
+		}
 	}
+
 	return &ResolverMap{
-		Types: typeMap,
+		Types:          typeMap,
+		byName:         byName,
+		resolveTypeFns: resolveTypeFns,
+	}, nil
+}
+
+// unionFields builds the synthetic field set for a union type: the set-union
+// of fields declared by every possible object type. When more than one
+// possible type declares a field with the same name, that's only allowed
+// when either (a) every declaring type agrees on the field's GraphQL type,
+// in which case a single resolver is registered that dispatches to whichever
+// concrete type the value actually is, or (b) the caller has supplied an
+// override for the field, keyed "Union.Type.field", for every declaring
+// type whose GraphQL type differs from that of the first possible type
+// declaring the field. Otherwise it returns a
+// descriptive error naming the ambiguous field and its concrete types.
+func unionFields(u *schema.Union, byName map[string]*TypeResolver, inputResolvers map[string]ResolverFunc) (map[string]*FieldResolver, error) {
+	fieldTypes := make(map[string]common.Type)
+	owners := make(map[string][]string)
+	perOwnerResolver := make(map[string]map[string]ResolverFunc)
+
+	for _, possible := range u.PossibleTypes {
+		concrete, ok := byName[possible.Name]
+		if !ok {
+			continue
+		}
+		for name, fr := range concrete.Fields {
+			owners[name] = append(owners[name], possible.Name)
+			if perOwnerResolver[name] == nil {
+				perOwnerResolver[name] = make(map[string]ResolverFunc)
+			}
+			perOwnerResolver[name][possible.Name] = fr.ResolverFunc
+
+			existing, seen := fieldTypes[name]
+			if !seen {
+				fieldTypes[name] = fr.Type
+				continue
+			}
+			if existing.String() == fr.Type.String() {
+				continue
+			}
+
+			// types disagree: the caller must disambiguate this field for
+			// this concrete type explicitly.
+			overrideKey := u.Name + "." + possible.Name + "." + name
+			override, ok := inputResolvers[overrideKey]
+			if !ok {
+				return nil, errors.Errorf(
+					"field %q is declared with different types across the possible types of union %v (%v); "+
+						"register an override for it as %q", name, u.Name, owners[name], overrideKey)
+			}
+			perOwnerResolver[name][possible.Name] = override
+		}
+	}
+
+	fields := make(map[string]*FieldResolver)
+	for name, typ := range fieldTypes {
+		fields[name] = &FieldResolver{
+			Type:         typ,
+			ResolverFunc: dispatchUnionField(perOwnerResolver[name]),
+		}
+	}
+	return fields, nil
+}
+
+// dispatchUnionField returns a ResolverFunc for a union's synthetic field
+// that, at resolve time, picks the resolver registered for the concrete
+// type of params.Source.
+func dispatchUnionField(byOwner map[string]ResolverFunc) ResolverFunc {
+	return func(params Params) (interface{}, error) {
+		typename := typenameOf(params.Source)
+		if res, ok := byOwner[typename]; ok {
+			return res(params)
+		}
+		return nil, nil
 	}
 }
 
+func typenameOf(source interface{}) string {
+	if t, ok := source.(Typed); ok {
+		return t.Typename()
+	}
+	return ""
+}
+
 type Params struct {
 	Source interface{}
 	Args map[string]interface{}
@@ -86,7 +208,7 @@ func (p Params) Arg(name string) interface{} {
 }
 
 func (rm *ResolverMap) Resolve(typ schema.NamedType, field string, params Params) (interface{}, error) {
-	fieldResolver, err := rm.getFieldResolver(typ, field)
+	fieldResolver, err := rm.resolveFieldResolver(typ, field, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "resolver lookup")
 	}
@@ -97,6 +219,46 @@ func (rm *ResolverMap) Resolve(typ schema.NamedType, field string, params Params
 	return result, nil
 }
 
+// resolveFieldResolver looks up the resolver for typ.field. When typ is a
+// union, it first tries to dispatch to the concrete type's own resolver
+// (using __resolveType or the runtime Typed value), falling back to the
+// union's synthetic field resolver only when no concrete resolver exists.
+func (rm *ResolverMap) resolveFieldResolver(typ schema.NamedType, field string, params Params) (*FieldResolver, error) {
+	if union, ok := typ.(*schema.Union); ok {
+		if fr, ok := rm.concreteUnionFieldResolver(union, field, params); ok {
+			return fr, nil
+		}
+	}
+	return rm.getFieldResolver(typ, field)
+}
+
+func (rm *ResolverMap) concreteUnionFieldResolver(union *schema.Union, field string, params Params) (*FieldResolver, bool) {
+	typename := rm.resolveTypename(union, params)
+	if typename == "" {
+		return nil, false
+	}
+	concrete, ok := rm.byName[typename]
+	if !ok {
+		return nil, false
+	}
+	fr, ok := concrete.Fields[field]
+	return fr, ok
+}
+
+func (rm *ResolverMap) resolveTypename(union *schema.Union, params Params) string {
+	if name := typenameOf(params.Source); name != "" {
+		return name
+	}
+	if fn, ok := rm.resolveTypeFns[union.Name]; ok {
+		if result, err := fn(params); err == nil {
+			if name, ok := result.(string); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
 func (rm *ResolverMap) getFieldResolver(typ schema.NamedType, field string) (*FieldResolver, error) {
 	typeResolver, ok := rm.Types[typ]
 	if !ok {
@@ -137,4 +299,13 @@ func metaType(typeName string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// IsMetaType reports whether typeName is one of GraphQL's built-in
+// scalar/introspection types - the ones NewResolverMap skips when building
+// its TypeResolvers. Exported so other packages rendering a parsed Schema
+// (e.g. the debug CLI's SDL printer) can skip the same types and stay
+// consistent with what the resolver map actually serves.
+func IsMetaType(typeName string) bool {
+	return metaType(typeName)
+}
@@ -0,0 +1,163 @@
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/vektah/gqlgen/neelance/schema"
+)
+
+func mustParseSchema(t *testing.T, sdl string) *schema.Schema {
+	t.Helper()
+	sch, err := schema.Parse(sdl, false)
+	if err != nil {
+		t.Fatalf("parsing schema: %v", err)
+	}
+	return sch
+}
+
+func findType(t *testing.T, sch *schema.Schema, name string) schema.NamedType {
+	t.Helper()
+	for _, typ := range sch.Types {
+		if typ.TypeName() == name {
+			return typ
+		}
+	}
+	t.Fatalf("type %v not found in schema", name)
+	return nil
+}
+
+func TestNewResolverMap_NonOverlappingUnion(t *testing.T) {
+	sch := mustParseSchema(t, `
+		schema { query: Query }
+		type Query { search: SearchResult }
+		type Dog { name: String }
+		type Cat { lives: Int }
+		union SearchResult = Dog | Cat
+	`)
+
+	rm, err := NewResolverMap(sch, nil)
+	if err != nil {
+		t.Fatalf("NewResolverMap: %v", err)
+	}
+
+	fields := rm.Types[findType(t, sch, "SearchResult")].Fields
+	if _, ok := fields["name"]; !ok {
+		t.Errorf("expected union to expose Dog.name")
+	}
+	if _, ok := fields["lives"]; !ok {
+		t.Errorf("expected union to expose Cat.lives")
+	}
+}
+
+func TestNewResolverMap_OverlappingCompatibleUnion(t *testing.T) {
+	sch := mustParseSchema(t, `
+		schema { query: Query }
+		type Query { search: SearchResult }
+		type Dog { name: String }
+		type Cat { name: String }
+		union SearchResult = Dog | Cat
+	`)
+
+	rm, err := NewResolverMap(sch, nil)
+	if err != nil {
+		t.Fatalf("NewResolverMap: %v", err)
+	}
+
+	if _, ok := rm.Types[findType(t, sch, "SearchResult")].Fields["name"]; !ok {
+		t.Errorf("expected union to expose the shared name field")
+	}
+}
+
+type typedValue struct {
+	typename string
+}
+
+func (t typedValue) Typename() string { return t.typename }
+
+func TestResolve_DispatchesUnionFieldByTypename(t *testing.T) {
+	sch := mustParseSchema(t, `
+		schema { query: Query }
+		type Query { search: SearchResult }
+		type Dog { name: String }
+		type Cat { name: String }
+		union SearchResult = Dog | Cat
+	`)
+
+	rm, err := NewResolverMap(sch, map[string]ResolverFunc{
+		"Dog.name": func(params Params) (interface{}, error) { return "rex", nil },
+		"Cat.name": func(params Params) (interface{}, error) { return "whiskers", nil },
+	})
+	if err != nil {
+		t.Fatalf("NewResolverMap: %v", err)
+	}
+
+	union := findType(t, sch, "SearchResult")
+
+	result, err := rm.Resolve(union, "name", Params{Source: typedValue{"Dog"}})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if result != "rex" {
+		t.Errorf("expected Resolve to dispatch to Dog's resolver via Typed, got %v", result)
+	}
+
+	result, err = rm.Resolve(union, "name", Params{Source: typedValue{"Cat"}})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if result != "whiskers" {
+		t.Errorf("expected Resolve to dispatch to Cat's resolver via Typed, got %v", result)
+	}
+}
+
+func TestResolve_DispatchesUnionFieldByResolveTypeOverride(t *testing.T) {
+	sch := mustParseSchema(t, `
+		schema { query: Query }
+		type Query { search: SearchResult }
+		type Dog { name: String }
+		type Cat { name: String }
+		union SearchResult = Dog | Cat
+	`)
+
+	rm, err := NewResolverMap(sch, map[string]ResolverFunc{
+		"Dog.name":                   func(params Params) (interface{}, error) { return "rex", nil },
+		"Cat.name":                   func(params Params) (interface{}, error) { return "whiskers", nil },
+		"SearchResult.__resolveType": func(params Params) (interface{}, error) { return "Cat", nil },
+	})
+	if err != nil {
+		t.Fatalf("NewResolverMap: %v", err)
+	}
+
+	union := findType(t, sch, "SearchResult")
+
+	// Source doesn't implement Typed at all, so only the registered
+	// __resolveType override can tell Resolve which concrete type to use.
+	result, err := rm.Resolve(union, "name", Params{Source: struct{}{}})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if result != "whiskers" {
+		t.Errorf("expected Resolve to dispatch via the __resolveType override to Cat's resolver, got %v", result)
+	}
+}
+
+func TestNewResolverMap_AmbiguousUnion(t *testing.T) {
+	sch := mustParseSchema(t, `
+		schema { query: Query }
+		type Query { search: SearchResult }
+		type Dog { name: String }
+		type Cat { name: Int }
+		union SearchResult = Dog | Cat
+	`)
+
+	if _, err := NewResolverMap(sch, nil); err == nil {
+		t.Fatal("expected NewResolverMap to error on an ambiguous union field")
+	}
+
+	overrides := map[string]ResolverFunc{
+		"SearchResult.Cat.name": emptyResolver,
+	}
+	if _, err := NewResolverMap(sch, overrides); err != nil {
+		t.Fatalf("NewResolverMap with an override for the ambiguous field: %v", err)
+	}
+}
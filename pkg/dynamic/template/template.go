@@ -0,0 +1,212 @@
+// Package template generates a dynamic.ResolverMap's input resolvers from a
+// GraphQL schema and a user-authored text/template, so large schemas don't
+// require hand-writing one resolver per field.
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/vektah/gqlgen/neelance/common"
+	"github.com/vektah/gqlgen/neelance/schema"
+
+	"github.com/solo-io/sqoop/pkg/dynamic"
+)
+
+// FieldContext is the per-field execution context a template is rendered
+// with, at schema-load time, once per field. Args is the field's argument
+// *definitions* (names and types), for deciding which kind/shape of
+// resolver a field needs - it is not the runtime argument values supplied
+// on a given query. Runtime values are only available later, when a kind's
+// ResolverFunc actually runs against a query's dynamic.Params; see httpKind
+// for how a kind can defer part of its rendering to call time.
+type FieldContext struct {
+	Type       schema.NamedType
+	Field      *schema.Field
+	Args       common.InputValueList
+	ReturnType common.Type
+}
+
+// Kind builds a ResolverFunc from a field's rendered template output. Sqoop
+// ships "static" and "http" kinds; additional kinds can be registered with
+// RegisterKind.
+type Kind interface {
+	Build(ctx FieldContext, rendered string) (dynamic.ResolverFunc, error)
+}
+
+var kinds = map[string]Kind{}
+
+// RegisterKind makes a resolver kind available to templates under name.
+func RegisterKind(name string, kind Kind) {
+	kinds[name] = kind
+}
+
+func init() {
+	RegisterKind("static", staticKind{})
+	RegisterKind("http", httpKind{})
+	RegisterKind("sql", sqlKind{})
+}
+
+// Generate walks every field of every Object/Interface type in sch,
+// executes tmpl against that field's FieldContext, and builds the
+// resulting map of resolvers. This happens once per field, at schema-load
+// time - the template can only see the field's static shape (its name,
+// argument definitions, return type), not any particular query's argument
+// values. A field's rendered template output is expected to be of the
+// form "kind: params", e.g. "http: GET http://users/{id}" - the kind name
+// selects the registered Kind that builds the ResolverFunc, and everything
+// after the colon is passed to it as the rendered params. A kind is free
+// to defer part of its own params to call time, evaluated against the
+// query's dynamic.Params; httpKind does this for "{argName}" placeholders
+// in its URL. Fields that render to the empty string are left unresolved,
+// same as an absent inputResolvers entry in dynamic.NewResolverMap.
+func Generate(sch *schema.Schema, tmpl *template.Template) (map[string]dynamic.ResolverFunc, error) {
+	resolvers := make(map[string]dynamic.ResolverFunc)
+
+	for _, t := range sch.Types {
+		typeName, fields := fieldsOf(t)
+		if fields == nil {
+			continue
+		}
+
+		for _, f := range fields {
+			ctx := FieldContext{Type: t, Field: f, Args: f.Args, ReturnType: f.Type}
+
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, ctx); err != nil {
+				return nil, errors.Wrapf(err, "executing template for %v.%v", typeName, f.Name)
+			}
+			rendered := strings.TrimSpace(buf.String())
+			if rendered == "" {
+				continue
+			}
+
+			kindName, params := splitKind(rendered)
+			kind, ok := kinds[kindName]
+			if !ok {
+				return nil, errors.Errorf("%v.%v: unknown resolver kind %q", typeName, f.Name, kindName)
+			}
+			resolver, err := kind.Build(ctx, params)
+			if err != nil {
+				return nil, errors.Wrapf(err, "%v.%v: building %v resolver", typeName, f.Name, kindName)
+			}
+			resolvers[typeName+"."+f.Name] = resolver
+		}
+	}
+
+	return resolvers, nil
+}
+
+func fieldsOf(t schema.NamedType) (string, []*schema.Field) {
+	switch t := t.(type) {
+	case *schema.Object:
+		return t.Name, t.Fields
+	case *schema.Interface:
+		return t.Name, t.Fields
+	default:
+		return "", nil
+	}
+}
+
+func splitKind(rendered string) (kind, params string) {
+	idx := strings.IndexByte(rendered, ':')
+	if idx < 0 {
+		return strings.TrimSpace(rendered), ""
+	}
+	return strings.TrimSpace(rendered[:idx]), strings.TrimSpace(rendered[idx+1:])
+}
+
+// Funcs returns the helper functions templates can call: fields, argsOf,
+// isScalar, and isList, for introspecting a type from within a template.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"fields":   templateFields,
+		"argsOf":   templateArgsOf,
+		"isScalar": isScalar,
+		"isList":   isList,
+	}
+}
+
+func templateFields(t schema.NamedType) []*schema.Field {
+	_, fields := fieldsOf(t)
+	return fields
+}
+
+func templateArgsOf(f *schema.Field) common.InputValueList {
+	return f.Args
+}
+
+func isScalar(t common.Type) bool {
+	_, ok := t.(*schema.Scalar)
+	return ok
+}
+
+func isList(t common.Type) bool {
+	_, ok := t.(*common.List)
+	return ok
+}
+
+// staticKind treats the rendered output as a literal value to return
+// verbatim, useful for stubbing fields or returning constants.
+type staticKind struct{}
+
+func (staticKind) Build(_ FieldContext, rendered string) (dynamic.ResolverFunc, error) {
+	value := rendered
+	return func(params dynamic.Params) (interface{}, error) {
+		return value, nil
+	}, nil
+}
+
+// httpKind treats the rendered output as "METHOD URL" and invokes it,
+// decoding the response body as JSON. The URL may contain "{argName}"
+// placeholders; these are substituted from the query's dynamic.Params at
+// call time, not from the schema-time FieldContext, so the same compiled
+// resolver serves every query with different argument values.
+type httpKind struct{}
+
+var argPlaceholder = regexp.MustCompile(`\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+
+func (httpKind) Build(_ FieldContext, rendered string) (dynamic.ResolverFunc, error) {
+	parts := strings.SplitN(rendered, " ", 2)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("http resolver expects \"METHOD URL\", got %q", rendered)
+	}
+	method, urlTemplate := parts[0], strings.TrimSpace(parts[1])
+
+	return func(params dynamic.Params) (interface{}, error) {
+		url := argPlaceholder.ReplaceAllStringFunc(urlTemplate, func(placeholder string) string {
+			name := placeholder[1 : len(placeholder)-1]
+			return fmt.Sprint(params.Arg(name))
+		})
+
+		req, err := http.NewRequest(method, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		var result interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, errors.Wrapf(err, "decoding response from %v", url)
+		}
+		return result, nil
+	}, nil
+}
+
+// sqlKind is reserved but not yet implemented; it lets templates be
+// authored against the "sql" kind ahead of support landing.
+type sqlKind struct{}
+
+func (sqlKind) Build(_ FieldContext, rendered string) (dynamic.ResolverFunc, error) {
+	return nil, errors.Errorf("sql resolver kind is not yet implemented")
+}
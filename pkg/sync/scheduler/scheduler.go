@@ -0,0 +1,71 @@
+// Package scheduler runs registered sync jobs on configurable intervals, so
+// Sqoop can periodically reconcile state it can't rely on the informer's
+// watch events alone to catch (e.g. drift from out-of-band edits).
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/solo-io/solo-kit/pkg/utils/log"
+)
+
+// job is a named unit of recurring work.
+type job struct {
+	name     string
+	interval time.Duration
+	fn       func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs on their own interval. Each job's first
+// run is staggered with random jitter bounded by its interval, so many
+// jobs registered at the same time don't all fire in lockstep.
+type Scheduler struct {
+	jobs []job
+}
+
+// NewScheduler returns an empty Scheduler. Register jobs on it, then call
+// Start.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Register adds a job that runs fn every interval, once Start is called.
+func (s *Scheduler) Register(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, fn: fn})
+}
+
+// Start launches every registered job in its own goroutine. Each job runs
+// until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, j := range s.jobs {
+		go s.run(ctx, j)
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, j job) {
+	timer := time.NewTimer(jitter(j.interval))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			if err := j.fn(ctx); err != nil {
+				log.Printf("sync job %v failed: %v", j.name, err)
+			}
+			timer.Reset(j.interval)
+		}
+	}
+}
+
+// jitter returns a random duration in [0, interval), used to stagger a
+// job's first run.
+func jitter(interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(interval)))
+}